@@ -0,0 +1,80 @@
+package passwordhasher
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Algorithm is implemented by a password hashing scheme that can be
+// registered with the package and dispatched to by the "$id$" prefix of
+// its encoded hashes, the same way htpasswd-style tools distinguish
+// "$2y$", "$apr1$", and "$6$" hashes in a single file.
+type Algorithm interface {
+	// Hash returns a new encoded hash of password.
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash.
+	Verify(password, hash string) error
+	// ID returns the algorithm's canonical name, e.g. "argon2id".
+	ID() string
+	// Prefix returns the token that appears between the first two "$"
+	// delimiters of an encoded hash produced by this algorithm, used to
+	// register it in the package's dispatch table.
+	Prefix() string
+}
+
+// aliasedAlgorithm is implemented by algorithms whose encoded hashes may
+// carry more than one prefix, such as bcrypt's "$2a$"/"$2b$"/"$2y$"
+// version markers.
+type aliasedAlgorithm interface {
+	Aliases() []string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Algorithm{}
+)
+
+// RegisterAlgorithm adds a to the package-level registry under a.Prefix()
+// (and any additional prefixes from Aliases(), if implemented), so that
+// Hasher.Verify can dispatch hashes produced by a. Registering an
+// algorithm under a prefix that is already taken replaces the previous
+// entry.
+func RegisterAlgorithm(a Algorithm) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[a.Prefix()] = a
+	if aliased, ok := a.(aliasedAlgorithm); ok {
+		for _, alias := range aliased.Aliases() {
+			registry[alias] = a
+		}
+	}
+}
+
+// lookupAlgorithm returns the Algorithm registered for hash's "$id$"
+// prefix.
+func lookupAlgorithm(hash string) (Algorithm, error) {
+	token := phcToken(hash)
+	if token == "" {
+		return nil, ErrInvalidHash
+	}
+	registryMu.RLock()
+	a, ok := registry[token]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("passwordhasher: unrecognized algorithm %q: %w", token, ErrInvalidHash)
+	}
+	return a, nil
+}
+
+// phcToken extracts the token between the first two "$" delimiters of a
+// PHC-style hash string, e.g. "argon2id" from
+// "$argon2id$v=19$m=...$salt$hash". It returns "" if hash does not start
+// with "$".
+func phcToken(hash string) string {
+	if !strings.HasPrefix(hash, "$") {
+		return ""
+	}
+	token, _, _ := strings.Cut(hash[1:], "$")
+	return token
+}
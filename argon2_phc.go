@@ -0,0 +1,54 @@
+package passwordhasher
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// encodeArgon2PHC renders an argon2 (id or i) hash in the PHC string
+// format shared by argon2idAlgorithm and argon2iAlgorithm:
+// "$<id>$v=19$m=...,t=...,p=...$<salt>$<hash>".
+func encodeArgon2PHC(id string, memory, time uint32, parallelism uint8, salt, sum []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		id, argon2.Version, memory, time, parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(sum))
+}
+
+// parseArgon2PHC parses a PHC-encoded argon2 hash produced by
+// encodeArgon2PHC, checking that its algorithm token matches wantID and
+// its version matches the argon2 package's current version.
+func parseArgon2PHC(wantID, hash string) (memory, time uint32, parallelism uint8, salt, sum []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+	if parts[1] != wantID {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, scanErr := fmt.Sscanf(parts[2], "v=%d", &version); scanErr != nil || version != argon2.Version {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+
+	var p uint32
+	if _, scanErr := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &p); scanErr != nil {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+	parallelism = uint8(p)
+
+	salt, decodeErr := base64.RawStdEncoding.DecodeString(parts[4])
+	if decodeErr != nil {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+	sum, decodeErr = base64.RawStdEncoding.DecodeString(parts[5])
+	if decodeErr != nil {
+		return 0, 0, 0, nil, nil, ErrInvalidHash
+	}
+
+	return memory, time, parallelism, salt, sum, nil
+}
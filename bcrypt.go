@@ -0,0 +1,56 @@
+package passwordhasher
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	RegisterAlgorithm(&bcryptAlgorithm{cost: bcrypt.DefaultCost})
+}
+
+// bcryptAlgorithm hashes with bcrypt, for services migrating a legacy
+// bcrypt database to this package without a flag day: existing bcrypt
+// hashes keep verifying while new hashes are produced by the Hasher's
+// configured argon2id.
+type bcryptAlgorithm struct {
+	cost int
+}
+
+func (a *bcryptAlgorithm) ID() string     { return "bcrypt" }
+func (a *bcryptAlgorithm) Prefix() string { return "2a" }
+
+// Aliases reports the other bcrypt version markers so hashes produced by
+// any bcrypt implementation dispatch here, matching htpasswd's handling of
+// "$2a$"/"$2b$"/"$2y$".
+func (a *bcryptAlgorithm) Aliases() []string { return []string{"2", "2b", "2y"} }
+
+func (a *bcryptAlgorithm) Hash(password string) (string, error) {
+	sum, err := bcrypt.GenerateFromPassword([]byte(password), a.cost)
+	if err != nil {
+		return "", fmt.Errorf("passwordhasher: bcrypt hash: %w", err)
+	}
+	return string(sum), nil
+}
+
+func (a *bcryptAlgorithm) Verify(password, hash string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		// bcrypt's own cost is independent of argon2id's; run the shared
+		// dummy work too so a bcrypt mismatch costs about the same as a
+		// mismatch against any other registered algorithm.
+		_ = runDummyWork(password)
+		return ErrPasswordMismatch
+	default:
+		// A malformed or short hash makes CompareHashAndPassword return
+		// immediately without doing any KDF work at all; pay the same
+		// dummy cost as a genuine mismatch rather than exiting cheaply.
+		_ = runDummyWork(password)
+		return fmt.Errorf("passwordhasher: bcrypt verify: %w: %w", ErrInvalidHash, err)
+	}
+}
@@ -0,0 +1,137 @@
+package passwordhasher
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// calibrationTolerance bounds how close CalibrateParameters must land to
+// target before it stops adjusting: within +/-10%.
+const calibrationTolerance = 0.10
+
+// maxCalibrationIterations caps how many benchmark hashes
+// CalibrateParameters will run before giving up and returning its best
+// effort, so calibration can't loop indefinitely on an unusual host.
+const maxCalibrationIterations = 24
+
+// CalibrateParameters benchmarks argon2id on the current host and returns
+// memory (m, KiB), time (t) and parallelism (p) parameters such that a
+// single Hash call takes approximately target. Parallelism is set to
+// runtime.NumCPU(), capped at 8 (argon2id sees diminishing returns
+// beyond that). When under target, memory is grown first, since it is the
+// dominant cost knob recommended by OWASP, and time cost is raised once
+// memory reaches maxMemoryKiB. When over target, time cost is lowered
+// first, and memory is halved only once time cost is down to 1. A
+// maxMemoryKiB of 0 uses DefaultMemory as the ceiling.
+//
+// This lets operators deploy the module across heterogeneous hardware —
+// a beefy login server and a small worker both get a Hasher tuned to the
+// same target latency — without hand-tuning constants like
+// m=65536,t=3,p=2.
+func CalibrateParameters(target time.Duration, maxMemoryKiB uint32) (m, t, p uint32, err error) {
+	if target <= 0 {
+		return 0, 0, 0, fmt.Errorf("passwordhasher: calibration target must be positive")
+	}
+	if maxMemoryKiB == 0 {
+		maxMemoryKiB = DefaultMemory
+	}
+
+	parallelism := uint32(runtime.NumCPU())
+	if parallelism > 8 {
+		parallelism = 8
+	} else if parallelism == 0 {
+		parallelism = 1
+	}
+
+	memory := uint32(19 * 1024) // OWASP floor for argon2id.
+	if memory > maxMemoryKiB {
+		memory = maxMemoryKiB
+	}
+	timeCost := uint32(1)
+
+	for i := 0; i < maxCalibrationIterations; i++ {
+		elapsed, err := benchmarkArgon2id(memory, timeCost, uint8(parallelism))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+
+		ratio := float64(elapsed) / float64(target)
+		if ratio >= 1-calibrationTolerance && ratio <= 1+calibrationTolerance {
+			return memory, timeCost, parallelism, nil
+		}
+
+		switch {
+		case elapsed < target && memory < maxMemoryKiB:
+			memory = nextMemoryStep(memory, maxMemoryKiB, ratio)
+		case elapsed < target:
+			timeCost++
+		case timeCost > 1:
+			timeCost--
+		case memory > 8*1024:
+			memory /= 2
+		default:
+			// Already at the minimum memory and time cost but still over
+			// target: this host is simply too slow to hit it at p
+			// parallelism. Return the floor rather than loop forever.
+			return memory, timeCost, parallelism, nil
+		}
+	}
+
+	return memory, timeCost, parallelism, nil
+}
+
+// nextMemoryStep grows memory towards maxMemoryKiB, scaling the step by
+// how far off ratio (elapsed/target) is so calibration converges in a
+// handful of iterations rather than doubling blindly.
+func nextMemoryStep(memory, maxMemoryKiB uint32, ratio float64) uint32 {
+	factor := 1 / ratio
+	if factor > 4 {
+		factor = 4
+	}
+	next := uint32(float64(memory) * factor)
+	if next <= memory {
+		next = memory + 1024
+	}
+	if next > maxMemoryKiB {
+		next = maxMemoryKiB
+	}
+	return next
+}
+
+// benchmarkArgon2id times a single argon2id hash at the given parameters
+// against a fixed benchmark password.
+func benchmarkArgon2id(memory, timeCost uint32, parallelism uint8) (time.Duration, error) {
+	alg := &argon2idAlgorithm{
+		memory:      memory,
+		time:        timeCost,
+		parallelism: parallelism,
+		saltLength:  DefaultSaltLength,
+		keyLength:   DefaultKeyLength,
+	}
+	start := time.Now()
+	if _, err := alg.Hash("passwordhasher-calibration-benchmark"); err != nil {
+		return 0, fmt.Errorf("passwordhasher: calibration benchmark: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+// NewCalibratedHasher returns a Hasher whose argon2id parameters have been
+// calibrated, via CalibrateParameters, to take approximately target per
+// Hash call on the current host, using the package's default memory
+// ceiling.
+func NewCalibratedHasher(target time.Duration) (*Hasher, error) {
+	memory, timeCost, parallelism, err := CalibrateParameters(target, DefaultMemory)
+	if err != nil {
+		return nil, err
+	}
+	return &Hasher{
+		alg: &argon2idAlgorithm{
+			memory:      memory,
+			time:        timeCost,
+			parallelism: uint8(parallelism),
+			saltLength:  DefaultSaltLength,
+			keyLength:   DefaultKeyLength,
+		},
+	}, nil
+}
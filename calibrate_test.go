@@ -0,0 +1,81 @@
+package passwordhasher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalibrateParameters_WithinTolerance(t *testing.T) {
+	const target = 40 * time.Millisecond
+
+	memory, timeCost, parallelism, err := CalibrateParameters(target, 0)
+	if err != nil {
+		t.Fatalf("CalibrateParameters failed: %v", err)
+	}
+	if memory == 0 || timeCost == 0 || parallelism == 0 {
+		t.Fatalf("expected non-zero parameters, got m=%d t=%d p=%d", memory, timeCost, parallelism)
+	}
+
+	elapsed, err := benchmarkArgon2id(memory, timeCost, uint8(parallelism))
+	if err != nil {
+		t.Fatalf("benchmarkArgon2id failed: %v", err)
+	}
+
+	// Wall-clock benchmarking is inherently noisier under a test runner than
+	// on a quiet production host, so this assertion uses a looser band than
+	// the ~10% CalibrateParameters itself targets; it exists to catch gross
+	// miscalibration, not scheduler jitter.
+	ratio := float64(elapsed) / float64(target)
+	if ratio < 0.25 || ratio > 4 {
+		t.Errorf("calibrated parameters produced %v, want within ~4x of target %v (ratio %.2f)", elapsed, target, ratio)
+	}
+}
+
+func TestNewCalibratedHasher(t *testing.T) {
+	h, err := NewCalibratedHasher(20 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewCalibratedHasher failed: %v", err)
+	}
+
+	hash, err := h.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if err := h.Verify("ChangeMe123!", hash); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+// TestCalibrateParameters_FloorBranch forces the loop past both the
+// memory-shrink and time-cost-decrement branches down to the floor, where
+// it must return rather than loop forever.
+func TestCalibrateParameters_FloorBranch(t *testing.T) {
+	memory, timeCost, parallelism, err := CalibrateParameters(1*time.Nanosecond, 0)
+	if err != nil {
+		t.Fatalf("CalibrateParameters failed: %v", err)
+	}
+	if memory == 0 || timeCost == 0 || parallelism == 0 {
+		t.Fatalf("expected non-zero floor parameters, got m=%d t=%d p=%d", memory, timeCost, parallelism)
+	}
+	if timeCost != 1 {
+		t.Errorf("expected time cost to stay at its floor of 1, got %d", timeCost)
+	}
+}
+
+func TestCalibrateParameters_InvalidTarget(t *testing.T) {
+	if _, _, _, err := CalibrateParameters(0, 0); err == nil {
+		t.Error("expected an error for a non-positive target")
+	}
+}
+
+func TestCalibrateParameters_MemoryCeiling(t *testing.T) {
+	const maxMemoryKiB = 8 * 1024
+
+	memory, _, _, err := CalibrateParameters(5*time.Second, maxMemoryKiB)
+	if err != nil {
+		t.Fatalf("CalibrateParameters failed: %v", err)
+	}
+	if memory > maxMemoryKiB {
+		t.Errorf("expected memory to respect the %d KiB ceiling, got %d", maxMemoryKiB, memory)
+	}
+}
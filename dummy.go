@@ -0,0 +1,56 @@
+package passwordhasher
+
+import (
+	"crypto/subtle"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// dummyHash is a package-internal, well-formed argon2id PHC string at the
+// package defaults. Its salt and hash fields are fixed placeholder bytes,
+// not the output of hashing any password, so no password matches it;
+// they exist purely to give runDummyWork a fixed, valid PHC string to
+// spend a real KDF computation on.
+const dummyHash = "$argon2id$v=19$m=65536,t=3,p=2$Tm9TdWNoU2FsdEFjdHVhbGx5$a2V5dGhpc2lzYWZha2VoYXNoZm9ydGltaW5ncGFkZGluZw"
+
+// runDummyWork performs a full argon2id computation against dummyHash and
+// always returns ErrPasswordMismatch (barring an internal error). It
+// exists so that error paths which would otherwise return early — a
+// malformed hash, an unrecognized algorithm prefix — cost the same as a
+// genuine mismatch, and so VerifyDummy can equalize the cost of a
+// known-user and unknown-user login attempt.
+func runDummyWork(password string) error {
+	memory, time, parallelism, salt, sum, err := parseArgon2PHC("argon2id", dummyHash)
+	if err != nil {
+		return err
+	}
+	computed := argon2.IDKey([]byte(password), salt, time, memory, parallelism, uint32(len(sum)))
+	if subtle.ConstantTimeCompare(sum, computed) == 1 {
+		return nil
+	}
+	return ErrPasswordMismatch
+}
+
+// VerifyDummy runs an argon2id computation at the package default
+// parameters (m=65536, t=3, p=2), against a fixed internal hash, and
+// returns ErrPasswordMismatch for any non-empty password (ErrEmptyPassword,
+// without KDF work, for an empty one). Auth service callers should invoke
+// it whenever a lookup fails to find a hash to compare against — e.g. the
+// username does not exist — so that the unknown-user path costs as much
+// as verifying a hash stored at those default parameters and username
+// enumeration can't be inferred from response timing:
+//
+//	hash, ok := userHashes[username]
+//	if !ok {
+//	    _ = hasher.VerifyDummy(password)
+//	    return ErrInvalidCredentials
+//	}
+//	if err := hasher.Verify(password, hash); err != nil {
+//	    return ErrInvalidCredentials
+//	}
+func (h *Hasher) VerifyDummy(password string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	return runDummyWork(password)
+}
@@ -0,0 +1,74 @@
+package passwordhasher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasher_VerifyDummy(t *testing.T) {
+	h := NewHasher()
+
+	if err := h.VerifyDummy("whatever-the-caller-typed"); err != ErrPasswordMismatch {
+		t.Errorf("expected ErrPasswordMismatch, got %v", err)
+	}
+	if err := h.VerifyDummy(""); err != ErrEmptyPassword {
+		t.Errorf("expected ErrEmptyPassword, got %v", err)
+	}
+}
+
+// TestVerify_MalformedHashStillReturnsError exercises the early-exit paths
+// that runDummyWork now sits in front of, for every registered algorithm,
+// to confirm the timing-equalization refactor didn't change their
+// observable error behavior.
+func TestVerify_MalformedHashStillReturnsError(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"unrecognized prefix", "$notarealalgorithm$v=19$m=65536,t=3,p=2$salt$hash"},
+		{"argon2id missing parts", "$argon2id$v=19$m=65536,t=3,p=2$salt"},
+		{"argon2i missing parts", "$argon2i$v=19$m=65536,t=3,p=2$salt"},
+		{"scrypt missing parts", "$scrypt$n=32768,r=8,p=1$salt"},
+		{"bcrypt truncated", "$2a$10$tooshort"},
+	}
+
+	h := NewHasher()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := h.Verify("password", tt.hash); err == nil {
+				t.Error("expected an error for a malformed hash")
+			}
+		})
+	}
+}
+
+func TestBcryptAlgorithm_Verify_Mismatch(t *testing.T) {
+	alg := &bcryptAlgorithm{cost: 4} // low cost so the test stays fast
+	hash, err := alg.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("bcrypt Hash failed: %v", err)
+	}
+	if err := alg.Verify("WrongPassword", hash); err != ErrPasswordMismatch {
+		t.Errorf("expected ErrPasswordMismatch, got %v", err)
+	}
+}
+
+func TestKeyedHasher_Verify_UnknownKeyID(t *testing.T) {
+	kh := NewKeyedHasher([]byte("pepper"))
+
+	hash, err := kh.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	tampered := strings.Replace(hash, "keyid=v1", "keyid=bogus", 1)
+	if tampered == hash {
+		t.Fatal("test setup: expected hash to contain keyid=v1")
+	}
+
+	if err := kh.Verify("ChangeMe123!", tampered); err == nil {
+		t.Error("expected an error for an unresolvable keyid")
+	}
+	if _, _, err := kh.VerifyAndUpgrade("ChangeMe123!", tampered); err == nil {
+		t.Error("expected an error for an unresolvable keyid")
+	}
+}
@@ -0,0 +1,120 @@
+// Package passwordhasher provides argon2id-based password hashing with a
+// PHC-formatted ("$argon2id$v=19$m=...,t=...,p=...$salt$hash") encoding,
+// compatible with the auth-service reference implementation.
+package passwordhasher
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Default argon2id parameters, matching the auth-service reference
+// configuration for interactive login.
+const (
+	DefaultMemory      uint32 = 65536
+	DefaultTime        uint32 = 3
+	DefaultParallelism uint8  = 2
+	DefaultSaltLength  uint32 = 16
+	DefaultKeyLength   uint32 = 32
+)
+
+var (
+	// ErrPasswordMismatch is returned by Verify when the password does not
+	// match the hash.
+	ErrPasswordMismatch = errors.New("passwordhasher: password does not match hash")
+	// ErrInvalidHash is returned when a hash string is malformed, uses an
+	// unrecognized algorithm, or otherwise cannot be parsed.
+	ErrInvalidHash = errors.New("passwordhasher: invalid hash format")
+	// ErrEmptyPassword is returned when Hash or Verify is called with an
+	// empty password.
+	ErrEmptyPassword = errors.New("passwordhasher: password must not be empty")
+)
+
+// Hasher hashes passwords using argon2id at a fixed set of cost
+// parameters, and verifies hashes produced by any registered Algorithm
+// (see Verify).
+type Hasher struct {
+	alg *argon2idAlgorithm
+}
+
+// NewHasher returns a Hasher configured with the package defaults
+// (m=65536, t=3, p=2), suitable for interactive login.
+func NewHasher() *Hasher {
+	return &Hasher{
+		alg: &argon2idAlgorithm{
+			memory:      DefaultMemory,
+			time:        DefaultTime,
+			parallelism: DefaultParallelism,
+			saltLength:  DefaultSaltLength,
+			keyLength:   DefaultKeyLength,
+		},
+	}
+}
+
+// NewCustomHasher returns a Hasher configured with the given argon2id cost
+// parameters and output key length.
+func NewCustomHasher(memory, time uint32, parallelism uint8, keyLength uint32) *Hasher {
+	return &Hasher{
+		alg: &argon2idAlgorithm{
+			memory:      memory,
+			time:        time,
+			parallelism: parallelism,
+			saltLength:  DefaultSaltLength,
+			keyLength:   keyLength,
+		},
+	}
+}
+
+// Hash generates a random salt and returns the PHC-encoded argon2id hash
+// of password at the Hasher's configured parameters. password is
+// PRECIS OpaqueString-normalized first (see normalizePassword) so that
+// visually identical passwords entered from different keyboards hash to
+// the same value.
+func (h *Hasher) Hash(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	return h.alg.Hash(normalizePassword(password))
+}
+
+// HashWithSalt returns the PHC-encoded argon2id hash of password using the
+// given salt instead of a randomly generated one. It exists mainly for
+// deterministic tests; Hash should be preferred in application code.
+// password is normalized exactly as Hash normalizes it.
+func (h *Hasher) HashWithSalt(password string, salt []byte) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	return h.alg.hashWithSalt(normalizePassword(password), salt)
+}
+
+// Verify reports whether password matches hash. password is normalized
+// exactly as Hash normalizes it, so a hash produced from one Unicode
+// form of a password verifies against any compatibility-equivalent form.
+// The algorithm used to verify is determined by the "$id$" prefix of
+// hash, dispatched through the package registry (see RegisterAlgorithm),
+// so hashes produced by any registered Algorithm can be verified
+// regardless of which Hasher created them.
+func (h *Hasher) Verify(password, hash string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	password = normalizePassword(password)
+	alg, err := lookupAlgorithm(hash)
+	if err != nil {
+		// Still pay the full KDF cost so a malformed or unrecognized hash
+		// can't be distinguished from a genuine mismatch by timing alone.
+		_ = runDummyWork(password)
+		return err
+	}
+	return alg.Verify(password, hash)
+}
+
+func randomSalt(n uint32) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("passwordhasher: generate salt: %w", err)
+	}
+	return salt, nil
+}
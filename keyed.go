@@ -0,0 +1,205 @@
+package passwordhasher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyProvider resolves a server-side pepper by key ID and reports which
+// key ID new hashes should be stamped with, so a KeyedHasher can rotate
+// its pepper (e.g. backed by AWS KMS or Vault Transit) without
+// invalidating hashes created under an older key.
+type KeyProvider interface {
+	// CurrentKeyID returns the key ID new hashes should use.
+	CurrentKeyID() string
+	// Key returns the pepper bytes for keyID.
+	Key(keyID string) ([]byte, error)
+}
+
+// NewStaticKeyProvider returns a KeyProvider serving a single long-lived
+// pepper under keyID.
+func NewStaticKeyProvider(keyID string, pepper []byte) KeyProvider {
+	return &staticKeyProvider{keyID: keyID, key: pepper}
+}
+
+type staticKeyProvider struct {
+	keyID string
+	key   []byte
+}
+
+func (s *staticKeyProvider) CurrentKeyID() string { return s.keyID }
+
+func (s *staticKeyProvider) Key(keyID string) ([]byte, error) {
+	if keyID != s.keyID {
+		return nil, fmt.Errorf("passwordhasher: unknown pepper key id %q", keyID)
+	}
+	return s.key, nil
+}
+
+// NewEnvKeyProvider returns a KeyProvider that reads its pepper from the
+// named environment variable on every call, so the secret can be rotated
+// by updating the process environment without a rebuild.
+func NewEnvKeyProvider(keyID, envVar string) KeyProvider {
+	return &envKeyProvider{keyID: keyID, envVar: envVar}
+}
+
+type envKeyProvider struct {
+	keyID  string
+	envVar string
+}
+
+func (e *envKeyProvider) CurrentKeyID() string { return e.keyID }
+
+func (e *envKeyProvider) Key(keyID string) ([]byte, error) {
+	if keyID != e.keyID {
+		return nil, fmt.Errorf("passwordhasher: unknown pepper key id %q", keyID)
+	}
+	v := os.Getenv(e.envVar)
+	if v == "" {
+		return nil, fmt.Errorf("passwordhasher: environment variable %s is not set", e.envVar)
+	}
+	return []byte(v), nil
+}
+
+// KeyedHasher wraps a Hasher with a server-side pepper: the password is
+// HMAC-SHA256'd with the pepper before it reaches argon2id, and the
+// active key ID is stamped into the PHC string's parameter segment (e.g.
+// "$argon2id$v=19$m=...,t=...,p=...,keyid=v2$salt$hash") so Verify can
+// select the matching pepper even after rotation. This provides
+// defense-in-depth against a database-only compromise, which an
+// unpeppered hash cannot resist since the attacker has everything needed
+// to brute-force it offline.
+type KeyedHasher struct {
+	*Hasher
+	keys KeyProvider
+}
+
+// NewKeyedHasher returns a KeyedHasher using a single static pepper under
+// key ID "v1". For rotating secrets backed by a secrets manager, use
+// NewKeyedHasherWithProvider with a custom KeyProvider instead.
+func NewKeyedHasher(pepper []byte) *KeyedHasher {
+	return NewKeyedHasherWithProvider(NewStaticKeyProvider("v1", pepper))
+}
+
+// NewKeyedHasherWithProvider returns a KeyedHasher backed by an arbitrary
+// KeyProvider.
+func NewKeyedHasherWithProvider(keys KeyProvider) *KeyedHasher {
+	return &KeyedHasher{Hasher: NewHasher(), keys: keys}
+}
+
+func pepperPassword(password string, pepper []byte) string {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return string(mac.Sum(nil))
+}
+
+// Hash peppers password with the provider's current key before hashing,
+// and stamps that key's ID into the returned PHC string. password is
+// PRECIS OpaqueString-normalized before peppering (see normalizePassword)
+// so the pepper, not the inner Hasher, sees the canonical form — the
+// inner Hasher normalizes the HMAC digest it's handed, which is not the
+// same thing.
+func (k *KeyedHasher) Hash(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	password = normalizePassword(password)
+	keyID := k.keys.CurrentKeyID()
+	pepper, err := k.keys.Key(keyID)
+	if err != nil {
+		return "", fmt.Errorf("passwordhasher: resolve pepper: %w", err)
+	}
+	hash, err := k.Hasher.Hash(pepperPassword(password, pepper))
+	if err != nil {
+		return "", err
+	}
+	return encodeKeyID(hash, keyID), nil
+}
+
+// Verify selects the pepper by the keyid stamped in hash and verifies
+// against it, so hashes created under a now-rotated-out key still verify.
+// password is normalized exactly as Hash normalizes it.
+func (k *KeyedHasher) Verify(password, hash string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	password = normalizePassword(password)
+	keyID, inner := decodeKeyID(hash)
+	pepper, err := k.keys.Key(keyID)
+	if err != nil {
+		// An unresolvable keyid is itself a verification failure; pay the
+		// same KDF cost as a genuine mismatch rather than exiting early.
+		_ = runDummyWork(password)
+		return fmt.Errorf("passwordhasher: resolve pepper: %w", err)
+	}
+	return k.Hasher.Verify(pepperPassword(password, pepper), inner)
+}
+
+// VerifyAndUpgrade verifies password against hash and, if the hash was
+// peppered under an older key ID or needs an argon2id rehash, returns a
+// fresh hash peppered under the provider's current key for the caller to
+// persist. password is normalized exactly as Hash normalizes it.
+func (k *KeyedHasher) VerifyAndUpgrade(password, hash string) (newHash string, upgraded bool, err error) {
+	if password == "" {
+		return "", false, ErrEmptyPassword
+	}
+	password = normalizePassword(password)
+	keyID, inner := decodeKeyID(hash)
+	pepper, err := k.keys.Key(keyID)
+	if err != nil {
+		_ = runDummyWork(password)
+		return "", false, fmt.Errorf("passwordhasher: resolve pepper: %w", err)
+	}
+	peppered := pepperPassword(password, pepper)
+
+	if err := k.Hasher.Verify(peppered, inner); err != nil {
+		return "", false, err
+	}
+
+	currentKeyID := k.keys.CurrentKeyID()
+	if keyID == currentKeyID && !k.Hasher.NeedsRehash(inner) {
+		return "", false, nil
+	}
+
+	currentPepper, err := k.keys.Key(currentKeyID)
+	if err != nil {
+		return "", false, fmt.Errorf("passwordhasher: resolve pepper: %w", err)
+	}
+	newInner, err := k.Hasher.Hash(pepperPassword(password, currentPepper))
+	if err != nil {
+		return "", false, err
+	}
+	return encodeKeyID(newInner, currentKeyID), true, nil
+}
+
+// encodeKeyID appends ",keyid=<keyID>" to the parameter segment (the
+// fourth "$"-delimited field) of a PHC-style hash string.
+func encodeKeyID(hash, keyID string) string {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return hash
+	}
+	parts[3] = parts[3] + ",keyid=" + keyID
+	return strings.Join(parts, "$")
+}
+
+// decodeKeyID extracts the ",keyid=..." suffix from a hash's parameter
+// segment, returning the key ID and the hash with that suffix removed
+// (i.e. the form the wrapped Hasher understands). It returns an empty
+// key ID, and hash unchanged, if no keyid is present.
+func decodeKeyID(hash string) (keyID, inner string) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return "", hash
+	}
+	idx := strings.Index(parts[3], ",keyid=")
+	if idx == -1 {
+		return "", hash
+	}
+	keyID = parts[3][idx+len(",keyid="):]
+	parts[3] = parts[3][:idx]
+	return keyID, strings.Join(parts, "$")
+}
@@ -0,0 +1,102 @@
+package passwordhasher
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type rotatingKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (r *rotatingKeyProvider) CurrentKeyID() string { return r.current }
+
+func (r *rotatingKeyProvider) Key(keyID string) ([]byte, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown pepper key %q", keyID)
+	}
+	return key, nil
+}
+
+func TestKeyedHasher_Rotation(t *testing.T) {
+	provider := &rotatingKeyProvider{
+		current: "v1",
+		keys:    map[string][]byte{"v1": []byte("pepper-v1")},
+	}
+	kh := NewKeyedHasherWithProvider(provider)
+
+	hash, err := kh.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strings.Contains(hash, "keyid=v1") {
+		t.Fatalf("expected hash to carry keyid=v1, got %q", hash)
+	}
+	if err := kh.Verify("ChangeMe123!", hash); err != nil {
+		t.Fatalf("Verify failed before rotation: %v", err)
+	}
+
+	// Rotate to a new key; the old hash must still verify under its
+	// original (now retired) key.
+	provider.keys["v2"] = []byte("pepper-v2")
+	provider.current = "v2"
+
+	if err := kh.Verify("ChangeMe123!", hash); err != nil {
+		t.Errorf("Verify failed for a hash under a retired key: %v", err)
+	}
+
+	newHash, upgraded, err := kh.VerifyAndUpgrade("ChangeMe123!", hash)
+	if err != nil {
+		t.Fatalf("VerifyAndUpgrade failed: %v", err)
+	}
+	if !upgraded || !strings.Contains(newHash, "keyid=v2") {
+		t.Fatalf("expected an upgrade to keyid=v2, got upgraded=%v newHash=%q", upgraded, newHash)
+	}
+	if err := kh.Verify("ChangeMe123!", newHash); err != nil {
+		t.Errorf("upgraded hash failed to verify: %v", err)
+	}
+
+	// Once the retired key is gone entirely, the old hash can no longer be
+	// verified.
+	delete(provider.keys, "v1")
+	if err := kh.Verify("ChangeMe123!", hash); err == nil {
+		t.Error("expected Verify to fail once the retired pepper key is removed")
+	}
+}
+
+func TestKeyedHasher_VerifyAndUpgrade_EmptyPassword(t *testing.T) {
+	kh := NewKeyedHasher([]byte("pepper"))
+	if _, _, err := kh.VerifyAndUpgrade("", "$argon2id$v=19$m=65536,t=3,p=2,keyid=v1$salt$hash"); err != ErrEmptyPassword {
+		t.Errorf("expected ErrEmptyPassword, got %v", err)
+	}
+}
+
+// TestKeyedHasher_NormalizationRoundTrip guards against the bug where
+// Hash/Verify peppered the raw password and only then handed the
+// already-peppered HMAC digest to the inner Hasher to normalize — which
+// normalizes the wrong string and silently defeats the Unicode guarantee
+// chunk0-4 added. A password peppered and hashed in one Unicode form must
+// verify against any compatibility-equivalent form.
+func TestKeyedHasher_NormalizationRoundTrip(t *testing.T) {
+	kh := NewKeyedHasher([]byte("pepper"))
+
+	const (
+		nfc = "Café123!"  // precomposed e-acute (U+00E9)
+		nfd = "Café123!" // "e" plus a combining acute accent (U+0301)
+	)
+
+	hash, err := kh.Hash(nfc)
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if err := kh.Verify(nfd, hash); err != nil {
+		t.Errorf("NFD-encoded password failed to verify against an NFC-hashed password: %v", err)
+	}
+
+	if _, _, err := kh.VerifyAndUpgrade(nfd, hash); err != nil {
+		t.Errorf("VerifyAndUpgrade failed for an NFD-encoded password against an NFC-hashed password: %v", err)
+	}
+}
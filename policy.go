@@ -0,0 +1,181 @@
+package passwordhasher
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode"
+
+	"golang.org/x/text/secure/precis"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrPasswordTooShort is returned by HashWithPolicy when password is
+	// shorter than Policy.MinLength.
+	ErrPasswordTooShort = errors.New("passwordhasher: password shorter than policy minimum length")
+	// ErrPasswordTooLong is returned by HashWithPolicy when password
+	// exceeds Policy.MaxLength. Enforcing a maximum defends argon2id
+	// against denial-of-service from multi-megabyte inputs.
+	ErrPasswordTooLong = errors.New("passwordhasher: password longer than policy maximum length")
+	// ErrPasswordTooWeak is returned by HashWithPolicy when password's
+	// estimated entropy is below Policy.MinEntropyBits.
+	ErrPasswordTooWeak = errors.New("passwordhasher: password does not meet minimum entropy requirement")
+	// ErrPasswordMissingClass is returned by HashWithPolicy when password
+	// lacks a character class required by Policy.
+	ErrPasswordMissingClass = errors.New("passwordhasher: password missing a required character class")
+	// ErrPasswordBreached is returned by HashWithPolicy when
+	// Policy.BreachChecker reports password as known-compromised.
+	ErrPasswordBreached = errors.New("passwordhasher: password found in a known breach corpus")
+)
+
+// BreachChecker reports whether a candidate password is known to have
+// appeared in a public breach corpus, e.g. via an HIBP k-anonymity
+// SHA-1-prefix lookup or an offline bloom filter of common passwords.
+type BreachChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+// Policy describes the constraints HashWithPolicy enforces on a password
+// before hashing it. The zero value requires only that the password be
+// at most 128 characters long (it sets no minimum length; DefaultPolicy
+// adds an 8-character minimum); set fields to opt into stricter checks.
+type Policy struct {
+	// MinLength is the minimum number of characters required. 0 disables
+	// the check.
+	MinLength int
+	// MaxLength is the maximum number of characters allowed. 0 defaults
+	// to 128, guarding against multi-megabyte passwords being fed to
+	// argon2id.
+	MaxLength int
+	// MinEntropyBits is the minimum Shannon entropy estimate, in bits,
+	// the password must carry. 0 disables the check.
+	MinEntropyBits float64
+	// RequireUpper, RequireLower, RequireDigit, and RequireSymbol each
+	// demand at least one character of the corresponding class.
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// BreachChecker, if set, is consulted after all other checks pass.
+	BreachChecker BreachChecker
+}
+
+// DefaultPolicy is a reasonable baseline for interactive account
+// registration: 8-128 characters, no character-class or breach
+// requirements.
+var DefaultPolicy = Policy{MinLength: 8, MaxLength: 128}
+
+// HashWithPolicy validates password against p and, if it passes, hashes
+// it exactly as Hash would (Hash normalizes password the same way
+// validate does, so the value stored and the value later Verified are
+// always the same normalized form).
+func (h *Hasher) HashWithPolicy(password string, p Policy) (string, error) {
+	normalized, err := p.validate(password)
+	if err != nil {
+		return "", err
+	}
+	return h.Hash(normalized)
+}
+
+// validate checks password against p and returns its PRECIS
+// OpaqueString-normalized form (see normalizePassword) so that callers
+// validate and subsequently hash the exact same string, and so visually
+// identical passwords entered from different keyboards are judged
+// consistently.
+func (p Policy) validate(password string) (string, error) {
+	normalized := normalizePassword(password)
+	length := len([]rune(normalized))
+
+	if p.MinLength > 0 && length < p.MinLength {
+		return "", ErrPasswordTooShort
+	}
+	maxLength := p.MaxLength
+	if maxLength == 0 {
+		maxLength = 128
+	}
+	if length > maxLength {
+		return "", ErrPasswordTooLong
+	}
+
+	if p.RequireUpper || p.RequireLower || p.RequireDigit || p.RequireSymbol {
+		var hasUpper, hasLower, hasDigit, hasSymbol bool
+		for _, r := range normalized {
+			switch {
+			case unicode.IsUpper(r):
+				hasUpper = true
+			case unicode.IsLower(r):
+				hasLower = true
+			case unicode.IsDigit(r):
+				hasDigit = true
+			case unicode.IsPunct(r), unicode.IsSymbol(r):
+				hasSymbol = true
+			}
+		}
+		switch {
+		case p.RequireUpper && !hasUpper:
+			return "", ErrPasswordMissingClass
+		case p.RequireLower && !hasLower:
+			return "", ErrPasswordMissingClass
+		case p.RequireDigit && !hasDigit:
+			return "", ErrPasswordMissingClass
+		case p.RequireSymbol && !hasSymbol:
+			return "", ErrPasswordMissingClass
+		}
+	}
+
+	if p.MinEntropyBits > 0 && shannonEntropyBits(normalized) < p.MinEntropyBits {
+		return "", ErrPasswordTooWeak
+	}
+
+	if p.BreachChecker != nil {
+		breached, err := p.BreachChecker.IsBreached(normalized)
+		if err != nil {
+			return "", fmt.Errorf("passwordhasher: breach check: %w", err)
+		}
+		if breached {
+			return "", ErrPasswordBreached
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizePassword applies NFKC, which folds compatibility-equivalent
+// Unicode forms together, followed by the PRECIS OpaqueString profile
+// (RFC 8265), so that passwords typed on different keyboards or input
+// methods but visually identical still compare equal. Input precis
+// rejects outright (e.g. unassigned code points) is passed through
+// unchanged so policy checks still run against something rather than
+// failing hashing with an opaque Unicode error.
+func normalizePassword(password string) string {
+	normalized, err := precis.OpaqueString.String(norm.NFKC.String(password))
+	if err != nil {
+		return password
+	}
+	return normalized
+}
+
+// shannonEntropyBits estimates the total Shannon entropy, in bits, of
+// password from its character frequency distribution. This is a coarse
+// approximation useful as a cheap floor, not a substitute for a full
+// zxcvbn-style pattern-aware estimator.
+func shannonEntropyBits(password string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	counts := make(map[rune]int, len(runes))
+	for _, r := range runes {
+		counts[r]++
+	}
+
+	n := float64(len(runes))
+	var bitsPerChar float64
+	for _, c := range counts {
+		freq := float64(c) / n
+		bitsPerChar -= freq * math.Log2(freq)
+	}
+	return bitsPerChar * n
+}
@@ -0,0 +1,89 @@
+package passwordhasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHasher_HashWithPolicy_Errors(t *testing.T) {
+	h := NewHasher()
+
+	tests := []struct {
+		name     string
+		password string
+		policy   Policy
+		wantErr  error
+	}{
+		{"too short", "short1", Policy{MinLength: 8}, ErrPasswordTooShort},
+		{"too long", strings.Repeat("a", 200), Policy{MaxLength: 64}, ErrPasswordTooLong},
+		{"missing upper", "alllower123", Policy{RequireUpper: true}, ErrPasswordMissingClass},
+		{"missing lower", "ALLUPPER123", Policy{RequireLower: true}, ErrPasswordMissingClass},
+		{"missing digit", "NoDigitsHere", Policy{RequireDigit: true}, ErrPasswordMissingClass},
+		{"missing symbol", "NoSymbols123", Policy{RequireSymbol: true}, ErrPasswordMissingClass},
+		{"too weak", "aaaaaaaa", Policy{MinEntropyBits: 40}, ErrPasswordTooWeak},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.HashWithPolicy(tt.password, tt.policy); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasher_HashWithPolicy_Success(t *testing.T) {
+	h := NewHasher()
+	hash, err := h.HashWithPolicy("ChangeMe123!", Policy{MinLength: 8, RequireUpper: true, RequireDigit: true})
+	if err != nil {
+		t.Fatalf("HashWithPolicy failed: %v", err)
+	}
+	if err := h.Verify("ChangeMe123!", hash); err != nil {
+		t.Errorf("Verify failed for policy-hashed password: %v", err)
+	}
+}
+
+type stubBreachChecker struct {
+	breached bool
+	err      error
+}
+
+func (s stubBreachChecker) IsBreached(string) (bool, error) { return s.breached, s.err }
+
+func TestHasher_HashWithPolicy_Breached(t *testing.T) {
+	h := NewHasher()
+	policy := Policy{MinLength: 1, BreachChecker: stubBreachChecker{breached: true}}
+	if _, err := h.HashWithPolicy("ChangeMe123!", policy); !errors.Is(err, ErrPasswordBreached) {
+		t.Errorf("got error %v, want ErrPasswordBreached", err)
+	}
+}
+
+func TestHasher_HashWithPolicy_BreachCheckerError(t *testing.T) {
+	h := NewHasher()
+	wantErr := errors.New("breach service unavailable")
+	policy := Policy{MinLength: 1, BreachChecker: stubBreachChecker{err: wantErr}}
+	if _, err := h.HashWithPolicy("ChangeMe123!", policy); !errors.Is(err, wantErr) {
+		t.Errorf("got error %v, want it to wrap %v", err, wantErr)
+	}
+}
+
+// TestHasher_HashWithPolicy_NormalizationRoundTrip guards against the bug
+// where HashWithPolicy validated the PRECIS-normalized form of a password
+// but hashed the original, unnormalized one: a password hashed in one
+// Unicode form must verify against any compatibility-equivalent form.
+func TestHasher_HashWithPolicy_NormalizationRoundTrip(t *testing.T) {
+	h := NewHasher()
+
+	const (
+		nfc = "Café123!"  // precomposed e-acute
+		nfd = "Café123!" // e followed by a combining acute accent
+	)
+
+	hash, err := h.HashWithPolicy(nfc, Policy{MinLength: 1})
+	if err != nil {
+		t.Fatalf("HashWithPolicy failed: %v", err)
+	}
+	if err := h.Verify(nfd, hash); err != nil {
+		t.Errorf("NFD-encoded password failed to verify against an NFC-hashed password: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package passwordhasher
+
+// NeedsRehash reports whether hash should be regenerated under h's
+// configured algorithm and parameters: either because hash was produced
+// by a different (weaker) algorithm, or because its argon2id cost
+// parameters are lower than h's. It does not validate hash beyond what is
+// needed to make that determination; malformed hashes are treated as
+// needing a rehash.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if phcToken(hash) != h.alg.Prefix() {
+		return true
+	}
+	memory, time, parallelism, _, _, err := parseArgon2PHC("argon2id", hash)
+	if err != nil {
+		return true
+	}
+	return memory < h.alg.memory || time < h.alg.time || parallelism < h.alg.parallelism
+}
+
+// VerifyAndUpgrade verifies password against hash and, if it matches but
+// hash was produced by a weaker algorithm or lower cost parameters than
+// h's current configuration, returns a freshly computed hash at h's
+// parameters for the caller to persist. This lets a service migrate
+// legacy bcrypt/scrypt databases, or ratchet up argon2id cost over time,
+// by upgrading each user transparently on their next successful login
+// rather than running a database migration.
+//
+// upgraded is false, and newHash is empty, whenever hash already matches
+// h's current algorithm and parameters. err is non-nil whenever
+// verification itself fails, in which case newHash and upgraded are
+// always zero values.
+func (h *Hasher) VerifyAndUpgrade(password, hash string) (newHash string, upgraded bool, err error) {
+	if err := h.Verify(password, hash); err != nil {
+		return "", false, err
+	}
+	if !h.NeedsRehash(hash) {
+		return "", false, nil
+	}
+	newHash, err = h.Hash(password)
+	if err != nil {
+		return "", false, err
+	}
+	return newHash, true, nil
+}
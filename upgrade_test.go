@@ -0,0 +1,61 @@
+package passwordhasher
+
+import "testing"
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	weak := NewCustomHasher(8*1024, 1, 1, 32)
+	strong := NewHasher()
+
+	weakHash, err := weak.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strong.NeedsRehash(weakHash) {
+		t.Error("expected NeedsRehash to report true for weaker parameters")
+	}
+
+	strongHash, err := strong.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if strong.NeedsRehash(strongHash) {
+		t.Error("expected NeedsRehash to report false for a hash at current parameters")
+	}
+
+	if !strong.NeedsRehash("not-a-valid-hash") {
+		t.Error("expected NeedsRehash to report true for a malformed hash")
+	}
+}
+
+func TestHasher_VerifyAndUpgrade(t *testing.T) {
+	weak := NewCustomHasher(8*1024, 1, 1, 32)
+	strong := NewHasher()
+
+	hash, err := weak.Hash("ChangeMe123!")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+
+	newHash, upgraded, err := strong.VerifyAndUpgrade("ChangeMe123!", hash)
+	if err != nil {
+		t.Fatalf("VerifyAndUpgrade failed: %v", err)
+	}
+	if !upgraded || newHash == "" {
+		t.Fatalf("expected an upgrade for a weaker hash, got upgraded=%v newHash=%q", upgraded, newHash)
+	}
+	if err := strong.Verify("ChangeMe123!", newHash); err != nil {
+		t.Errorf("upgraded hash failed to verify: %v", err)
+	}
+
+	again, upgradedAgain, err := strong.VerifyAndUpgrade("ChangeMe123!", newHash)
+	if err != nil {
+		t.Fatalf("VerifyAndUpgrade failed: %v", err)
+	}
+	if upgradedAgain || again != "" {
+		t.Errorf("expected no upgrade once a hash is already at current parameters, got upgraded=%v newHash=%q", upgradedAgain, again)
+	}
+
+	if _, _, err := strong.VerifyAndUpgrade("WrongPassword", hash); err != ErrPasswordMismatch {
+		t.Errorf("expected ErrPasswordMismatch for wrong password, got %v", err)
+	}
+}